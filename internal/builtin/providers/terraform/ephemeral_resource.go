@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package terraform
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/internal/providers"
+)
+
+// ephemeralResourceType is the shape common to every ephemeral resource type
+// implemented directly inside this built-in provider. Unlike the "real"
+// provider plugin protocol, these handlers run in-process and can therefore
+// share simple Go function values rather than going through RPC request
+// structs for their actual behavior.
+type ephemeralResourceType struct {
+	schema providers.Schema
+	open   func(providers.OpenEphemeralResourceRequest) providers.OpenEphemeralResourceResponse
+	renew  func(providers.RenewEphemeralResourceRequest) providers.RenewEphemeralResourceResponse
+	close  func(providers.CloseEphemeralResourceRequest) providers.CloseEphemeralResourceResponse
+}
+
+// ephemeralResourceTypes is the registry of every ephemeral resource type
+// this provider supports, keyed by the resource type name. Each one is
+// implemented in its own resource_ephemeral_*.go file.
+var ephemeralResourceTypes = map[string]ephemeralResourceType{
+	"terraform_random_number":  ephemeralRandomNumberResourceType(),
+	"terraform_ephemeral_time": ephemeralTimeResourceType(),
+	"terraform_ephemeral_uuid": ephemeralUUIDResourceType(),
+	"terraform_ephemeral_env":  ephemeralEnvResourceType(),
+}
+
+
+// EphemeralResourceTypes implements providers.Interface.
+func (p *Provider) EphemeralResourceTypes() map[string]providers.Schema {
+	ret := make(map[string]providers.Schema, len(ephemeralResourceTypes))
+	for name, rt := range ephemeralResourceTypes {
+		ret[name] = rt.schema
+	}
+	return ret
+}
+
+// ValidateEphemeralResourceConfig implements providers.Interface.
+//
+// None of the built-in ephemeral resource types currently have any
+// validation rules beyond what's already enforced by their schemas, so this
+// just confirms the type name is one we recognize.
+func (p *Provider) ValidateEphemeralResourceConfig(req providers.ValidateEphemeralResourceConfigRequest) providers.ValidateEphemeralResourceConfigResponse {
+	var resp providers.ValidateEphemeralResourceConfigResponse
+	if _, ok := ephemeralResourceTypes[req.TypeName]; !ok {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource type %q", req.TypeName))
+	}
+	return resp
+}
+
+// OpenEphemeralResource implements providers.Interface.
+func (p *Provider) OpenEphemeralResource(req providers.OpenEphemeralResourceRequest) providers.OpenEphemeralResourceResponse {
+	rt, ok := ephemeralResourceTypes[req.TypeName]
+	if !ok {
+		var resp providers.OpenEphemeralResourceResponse
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource type %q", req.TypeName))
+		return resp
+	}
+	return rt.open(req)
+}
+
+// RenewEphemeralResource implements providers.Interface.
+func (p *Provider) RenewEphemeralResource(req providers.RenewEphemeralResourceRequest) providers.RenewEphemeralResourceResponse {
+	rt, ok := ephemeralResourceTypes[req.TypeName]
+	if !ok || rt.renew == nil {
+		var resp providers.RenewEphemeralResourceResponse
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource type %q", req.TypeName))
+		return resp
+	}
+	return rt.renew(req)
+}
+
+// CloseEphemeralResource implements providers.Interface.
+func (p *Provider) CloseEphemeralResource(req providers.CloseEphemeralResourceRequest) providers.CloseEphemeralResourceResponse {
+	rt, ok := ephemeralResourceTypes[req.TypeName]
+	if !ok || rt.close == nil {
+		var resp providers.CloseEphemeralResourceResponse
+		return resp
+	}
+	return rt.close(req)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package terraform
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/internal/configs/configschema"
+	"github.com/hashicorp/terraform/internal/providers"
+)
+
+// ephemeralEnvResourceType implements terraform_ephemeral_env, which reads a
+// named environment variable from the process running Terraform. Because
+// ephemeral resources never persist their value, this is a safe way to pull
+// a secret (an API token, say) out of the environment and pass it to a
+// provider without it ever being written into the plan or state.
+func ephemeralEnvResourceType() ephemeralResourceType {
+	return ephemeralResourceType{
+		schema: providers.Schema{
+			Block: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"name": {
+						Type:     cty.String,
+						Required: true,
+					},
+					"fail_if_unset": {
+						Type:     cty.Bool,
+						Optional: true,
+					},
+					"value": {
+						Type:      cty.String,
+						Computed:  true,
+						Sensitive: true,
+					},
+				},
+			},
+		},
+		open: func(req providers.OpenEphemeralResourceRequest) providers.OpenEphemeralResourceResponse {
+			var resp providers.OpenEphemeralResourceResponse
+
+			name := req.Config.GetAttr("name").AsString()
+			value, set := os.LookupEnv(name)
+
+			failIfUnset := false
+			failAttr := req.Config.GetAttr("fail_if_unset")
+			if !failAttr.IsNull() {
+				failIfUnset = failAttr.True()
+			}
+
+			if !set && failIfUnset {
+				resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("environment variable %q is not set", name))
+				return resp
+			}
+
+			resp.Result = cty.ObjectVal(map[string]cty.Value{
+				"name":          cty.StringVal(name),
+				"fail_if_unset": failAttr,
+				"value":         cty.StringVal(value),
+			})
+			return resp
+		},
+	}
+}
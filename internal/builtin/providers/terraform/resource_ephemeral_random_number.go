@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package terraform
+
+import (
+	"math/rand"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/internal/configs/configschema"
+	"github.com/hashicorp/terraform/internal/providers"
+)
+
+// ephemeralRandomNumberResourceType is a placeholder ephemeral resource type
+// used during the initial development of ephemeral resources: it returns a
+// fresh random number on every open and never needs to be renewed or
+// closed.
+func ephemeralRandomNumberResourceType() ephemeralResourceType {
+	return ephemeralResourceType{
+		schema: providers.Schema{
+			Block: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"value": {
+						Type:     cty.Number,
+						Computed: true,
+					},
+				},
+			},
+		},
+		open: func(req providers.OpenEphemeralResourceRequest) providers.OpenEphemeralResourceResponse {
+			var resp providers.OpenEphemeralResourceResponse
+			resp.Result = cty.ObjectVal(map[string]cty.Value{
+				"value": cty.NumberIntVal(int64(rand.Int63())),
+			})
+			return resp
+		},
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package terraform
+
+import (
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/internal/configs/configschema"
+	"github.com/hashicorp/terraform/internal/providers"
+)
+
+// ephemeralTimeRenewInterval is how far in the future terraform_ephemeral_time
+// asks to be renewed, both on open and on each renewal.
+const ephemeralTimeRenewInterval = 10 * time.Minute
+
+// ephemeralTimeResourceType implements terraform_ephemeral_time, which
+// returns the time at which it was opened and asks to be renewed every
+// ephemeralTimeRenewInterval. A renewal only schedules the next renewal: the
+// returned timestamp stays the one taken at open. It never persists anything
+// to state, unlike the time_static/time_offset resources in the time provider.
+func ephemeralTimeResourceType() ephemeralResourceType {
+	schema := providers.Schema{
+		Block: &configschema.Block{
+			Attributes: map[string]*configschema.Attribute{
+				"rfc3339": {
+					Type:     cty.String,
+					Computed: true,
+				},
+				"unix": {
+					Type:     cty.Number,
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	resultFor := func(t time.Time) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"rfc3339": cty.StringVal(t.Format(time.RFC3339)),
+			"unix":    cty.NumberIntVal(t.Unix()),
+		})
+	}
+
+	return ephemeralResourceType{
+		schema: schema,
+		open: func(req providers.OpenEphemeralResourceRequest) providers.OpenEphemeralResourceResponse {
+			var resp providers.OpenEphemeralResourceResponse
+			resp.Result = resultFor(time.Now())
+			resp.RenewAt = time.Now().Add(ephemeralTimeRenewInterval)
+			return resp
+		},
+		renew: func(req providers.RenewEphemeralResourceRequest) providers.RenewEphemeralResourceResponse {
+			var resp providers.RenewEphemeralResourceResponse
+			resp.RenewAt = time.Now().Add(ephemeralTimeRenewInterval)
+			return resp
+		},
+	}
+}
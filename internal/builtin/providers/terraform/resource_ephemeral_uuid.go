@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package terraform
+
+import (
+	"github.com/hashicorp/go-uuid"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/internal/configs/configschema"
+	"github.com/hashicorp/terraform/internal/providers"
+)
+
+// ephemeralUUIDResourceType implements terraform_ephemeral_uuid, which
+// generates a new random UUID on every open. Unlike the random_uuid managed
+// resource, the value is never written to state, so it's suitable for
+// one-off identifiers (e.g. a request ID passed to an external API) that
+// shouldn't accumulate as persistent resources.
+func ephemeralUUIDResourceType() ephemeralResourceType {
+	return ephemeralResourceType{
+		schema: providers.Schema{
+			Block: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"result": {
+						Type:     cty.String,
+						Computed: true,
+					},
+				},
+			},
+		},
+		open: func(req providers.OpenEphemeralResourceRequest) providers.OpenEphemeralResourceResponse {
+			var resp providers.OpenEphemeralResourceResponse
+			id, err := uuid.GenerateUUID()
+			if err != nil {
+				resp.Diagnostics = resp.Diagnostics.Append(err)
+				return resp
+			}
+			resp.Result = cty.ObjectVal(map[string]cty.Value{
+				"result": cty.StringVal(id),
+			})
+			return resp
+		},
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package deferring tracks the resource instances whose changes have been
+// deferred to a future run during a single graph walk, so that evaluating a
+// reference to one of them can be deferred in turn rather than failing.
+package deferring
+
+import (
+	"sync"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/providers"
+)
+
+// Deferred is the EvalContext.Deferrals() accessor's return type: a
+// per-graph-walk tracker of which resource instances have had their changes
+// deferred, and why.
+type Deferred struct {
+	mu                sync.Mutex
+	resourceInstances map[addrs.AbsResourceInstance]providers.DeferredReason
+}
+
+// NewDeferred returns a new, empty Deferred tracker for a graph walk.
+func NewDeferred() *Deferred {
+	return &Deferred{
+		resourceInstances: make(map[addrs.AbsResourceInstance]providers.DeferredReason),
+	}
+}
+
+// ReportEphemeralResourceInstanceDeferred records that the given ephemeral
+// resource instance could not be opened this round, for the given reason.
+//
+// Like the managed-resource equivalent, this doesn't itself cause anything
+// else to be deferred: whatever evaluates a reference to addr is
+// responsible for calling ResourceInstanceDeferred and deferring its own
+// work in turn, the same way it already does for a deferred managed
+// resource.
+func (d *Deferred) ReportEphemeralResourceInstanceDeferred(addr addrs.AbsResourceInstance, reason providers.DeferredReason) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.resourceInstances[addr] = reason
+}
+
+// ResourceInstanceDeferred returns the reason the given resource instance
+// was deferred, if it was.
+func (d *Deferred) ResourceInstanceDeferred(addr addrs.AbsResourceInstance) (reason providers.DeferredReason, deferred bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	reason, deferred = d.resourceInstances[addr]
+	return reason, deferred
+}
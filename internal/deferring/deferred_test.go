@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package deferring
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/providers"
+)
+
+func testInstAddr(name string) addrs.AbsResourceInstance {
+	return addrs.AbsResourceInstance{
+		Module: addrs.RootModuleInstance,
+		Resource: addrs.ResourceInstance{
+			Resource: addrs.Resource{
+				Mode: addrs.EphemeralResourceMode,
+				Type: "test_thing",
+				Name: name,
+			},
+		},
+	}
+}
+
+func TestDeferred_ephemeralResourceInstance(t *testing.T) {
+	d := NewDeferred()
+	addr := testInstAddr("example")
+
+	if _, deferred := d.ResourceInstanceDeferred(addr); deferred {
+		t.Fatalf("instance reported deferred before ReportEphemeralResourceInstanceDeferred was called")
+	}
+
+	d.ReportEphemeralResourceInstanceDeferred(addr, providers.DeferredReasonEphemeralOpen)
+
+	reason, deferred := d.ResourceInstanceDeferred(addr)
+	if !deferred {
+		t.Fatalf("expected instance to be reported as deferred")
+	}
+	if reason != providers.DeferredReasonEphemeralOpen {
+		t.Fatalf("wrong reason: got %q, want %q", reason, providers.DeferredReasonEphemeralOpen)
+	}
+
+	other := testInstAddr("other")
+	if _, deferred := d.ResourceInstanceDeferred(other); deferred {
+		t.Fatalf("unrelated instance must not be reported as deferred")
+	}
+}
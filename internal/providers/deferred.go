@@ -0,0 +1,19 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package providers
+
+// DeferredReason is the reason a provider, or Terraform Core itself, gives
+// for deferring a change rather than applying it in the current run.
+type DeferredReason string
+
+const (
+	// DeferredReasonEphemeralOpen means that an ephemeral resource instance
+	// could not be opened because the provider doesn't yet have enough
+	// information to do so -- for example, one of its configuration
+	// arguments is derived from an unknown value, or the upstream system it
+	// talks to isn't ready yet. Anything that refers to the ephemeral
+	// resource's result is deferred in turn, the same way it would be for a
+	// deferred managed resource.
+	DeferredReasonEphemeralOpen DeferredReason = "ephemeral_open"
+)
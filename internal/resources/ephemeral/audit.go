@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ephemeral
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+)
+
+// AuditSink receives a record of every significant event in an ephemeral
+// resource instance's lifecycle, so that operators have some durable record
+// of what a run opened even though ephemeral resources themselves are never
+// written to a plan or state file.
+//
+// Implementations must be safe to call concurrently: Resources may open,
+// renew, and close many instances at once during a graph walk.
+type AuditSink interface {
+	// Opened is called once an instance has been successfully opened (or,
+	// for a deferred open, once the deferral has been recorded).
+	Opened(addr addrs.AbsResourceInstance, typeName string, providerAddr addrs.AbsProviderConfig, timestamp time.Time)
+
+	// Renewed is called after each successful background renewal.
+	Renewed(addr addrs.AbsResourceInstance, timestamp time.Time)
+
+	// Closed is called once an instance has been closed, whether or not
+	// closing it succeeded. err is non-nil if closing reported errors;
+	// warning-only diagnostics give a nil err.
+	Closed(addr addrs.AbsResourceInstance, timestamp time.Time, err error)
+}
+
+// NullAuditSink discards every event. It's used whenever no audit sink has
+// been configured, so that the rest of this package doesn't need to treat a
+// missing sink as a special case.
+var NullAuditSink AuditSink = nullAuditSink{}
+
+type nullAuditSink struct{}
+
+func (nullAuditSink) Opened(addrs.AbsResourceInstance, string, addrs.AbsProviderConfig, time.Time) {}
+func (nullAuditSink) Renewed(addrs.AbsResourceInstance, time.Time)                                 {}
+func (nullAuditSink) Closed(addrs.AbsResourceInstance, time.Time, error)                           {}
@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ephemeral
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/providers"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// recordingAuditSink is an AuditSink that records the exact sequence of
+// events it receives, for asserting against in tests.
+type recordingAuditSink struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (s *recordingAuditSink) record(event string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+}
+
+func (s *recordingAuditSink) Opened(addr addrs.AbsResourceInstance, typeName string, providerAddr addrs.AbsProviderConfig, timestamp time.Time) {
+	s.record(fmt.Sprintf("opened %s (%s)", addr, typeName))
+}
+
+func (s *recordingAuditSink) Renewed(addr addrs.AbsResourceInstance, timestamp time.Time) {
+	s.record(fmt.Sprintf("renewed %s", addr))
+}
+
+func (s *recordingAuditSink) Closed(addr addrs.AbsResourceInstance, timestamp time.Time, err error) {
+	if err != nil {
+		s.record(fmt.Sprintf("closed %s (error: %s)", addr, err))
+		return
+	}
+	s.record(fmt.Sprintf("closed %s", addr))
+}
+
+var _ AuditSink = (*recordingAuditSink)(nil)
+
+type noopImpl struct{}
+
+func (noopImpl) Renew(ctx context.Context, req providers.EphemeralRenew) (*providers.EphemeralRenew, tfdiags.Diagnostics) {
+	return nil, nil
+}
+
+func (noopImpl) Close(ctx context.Context) tfdiags.Diagnostics {
+	return nil
+}
+
+func TestResourcesAuditSink_eventSequence(t *testing.T) {
+	sink := &recordingAuditSink{}
+	res := NewResources(sink, nil)
+
+	addr := testResourceInstanceAddr("test_thing", "example")
+
+	res.RegisterInstance(context.Background(), addr, ResourceInstanceRegistration{
+		Impl:         noopImpl{},
+		TypeName:     "test_thing",
+		ProviderAddr: testProviderAddr("test"),
+	})
+
+	diags := res.CloseInstances(context.Background(), addr.ConfigResource())
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %s", diags.Err())
+	}
+
+	want := []string{
+		fmt.Sprintf("opened %s (test_thing)", addr),
+		fmt.Sprintf("closed %s", addr),
+	}
+	sink.mu.Lock()
+	got := sink.events
+	sink.mu.Unlock()
+
+	if len(got) != len(want) {
+		t.Fatalf("wrong event sequence\ngot:  %v\nwant: %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("wrong event sequence\ngot:  %v\nwant: %v", got, want)
+		}
+	}
+}
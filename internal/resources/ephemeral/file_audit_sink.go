@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ephemeral
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+)
+
+// EphemeralAuditLogEnvVar is the environment variable operators will be able
+// to set to a file path to enable the default JSON-lines audit sink without
+// needing a CLI flag.
+//
+// Neither this nor a future -ephemeral-audit-log command line flag are
+// wired up to anything yet: that plumbing belongs in the command package,
+// which doesn't exist in this tree, so OpenAuditSinkFromEnv below has no
+// caller anywhere and auditing cannot currently be enabled from a real
+// terraform run. This package only provides the sink implementation and the
+// env var lookup for whichever code ends up constructing the Resources for
+// a graph walk to call.
+const EphemeralAuditLogEnvVar = "TF_EPHEMERAL_AUDIT_LOG"
+
+// auditRecord is the JSON shape written for every event by FileAuditSink.
+//
+// It deliberately has no field for the instance's value: the entire point
+// of an ephemeral resource is that its value (a lease token, a tunnel
+// credential, etc.) should never be written down anywhere, including here.
+type auditRecord struct {
+	Event     string    `json:"event"`
+	Address   string    `json:"address"`
+	Type      string    `json:"type,omitempty"`
+	Provider  string    `json:"provider,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// FileAuditSink is an AuditSink that appends one JSON object per line to an
+// io.Writer, typically an opened log file. It's the default sink used when
+// an operator enables ephemeral resource auditing via EphemeralAuditLogEnvVar.
+type FileAuditSink struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+}
+
+var _ AuditSink = (*FileAuditSink)(nil)
+
+// NewFileAuditSink returns a FileAuditSink that writes records to w. The
+// caller is responsible for opening (and eventually closing) w, typically
+// the file named by EphemeralAuditLogEnvVar.
+func NewFileAuditSink(w io.Writer) *FileAuditSink {
+	return &FileAuditSink{
+		w:   w,
+		enc: json.NewEncoder(w),
+	}
+}
+
+// OpenAuditSinkFromEnv opens the audit log file named by
+// EphemeralAuditLogEnvVar, if it's set, and returns a FileAuditSink writing
+// to it along with the opened file so the caller can close it once the run
+// is done. If the environment variable isn't set, it returns NullAuditSink
+// and a nil file, so callers can treat the result uniformly either way.
+//
+// Nothing in this tree calls this yet -- see the doc comment on
+// EphemeralAuditLogEnvVar -- so it's not reachable from an actual run until
+// whatever constructs the graph walk's Resources (NewResources) is updated
+// to call it and pass the result along.
+func OpenAuditSinkFromEnv() (AuditSink, *os.File, error) {
+	path := os.Getenv(EphemeralAuditLogEnvVar)
+	if path == "" {
+		return NullAuditSink, nil, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening %s (from %s): %w", path, EphemeralAuditLogEnvVar, err)
+	}
+	return NewFileAuditSink(f), f, nil
+}
+
+func (s *FileAuditSink) write(rec auditRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Encoding errors here are not actionable by the caller and auditing
+	// must never be allowed to fail a run, so we deliberately discard them.
+	_ = s.enc.Encode(rec)
+}
+
+// Opened implements AuditSink.
+func (s *FileAuditSink) Opened(addr addrs.AbsResourceInstance, typeName string, providerAddr addrs.AbsProviderConfig, timestamp time.Time) {
+	s.write(auditRecord{
+		Event:     "opened",
+		Address:   addr.String(),
+		Type:      typeName,
+		Provider:  providerAddr.String(),
+		Timestamp: timestamp,
+	})
+}
+
+// Renewed implements AuditSink.
+func (s *FileAuditSink) Renewed(addr addrs.AbsResourceInstance, timestamp time.Time) {
+	s.write(auditRecord{
+		Event:     "renewed",
+		Address:   addr.String(),
+		Timestamp: timestamp,
+	})
+}
+
+// Closed implements AuditSink.
+func (s *FileAuditSink) Closed(addr addrs.AbsResourceInstance, timestamp time.Time, err error) {
+	rec := auditRecord{
+		Event:     "closed",
+		Address:   addr.String(),
+		Timestamp: timestamp,
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	s.write(rec)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ephemeral
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/providers"
+)
+
+// renewJitterFraction is how much of the remaining time before a renewal is
+// due that we randomize the actual wake-up by, so that many instances with
+// similar lease durations (e.g. opened from the same Vault mount) don't all
+// renew in the same instant.
+const renewJitterFraction = 0.1
+
+// renewLoop runs in its own goroutine for as long as inst is registered and
+// needs periodic renewal. It sleeps until shortly before inst's current
+// RenewAt, asks the provider to renew, and repeats using whatever new
+// RenewAt the provider returns, until either ctx is done (the graph walk
+// stopped, or CloseInstances was called for this instance) or the provider
+// indicates no further renewal is needed.
+func (r *Resources) renewLoop(ctx context.Context, addr addrs.AbsResourceInstance, inst *registeredInstance) {
+	defer close(inst.done)
+
+	for {
+		inst.mu.Lock()
+		renewAt := inst.renewAt
+		private := inst.private
+		inst.mu.Unlock()
+
+		if renewAt.IsZero() {
+			return
+		}
+
+		if wait := renewJitteredWait(r.clock.Now(), renewAt); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.clock.After(wait):
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		log.Printf("[TRACE] ephemeral.Resources: renewing %s", addr)
+		release, semDiags := r.sems.Acquire(ctx, inst.ProviderAddr, fmt.Sprintf("renew of %s", addr))
+		if semDiags.HasErrors() {
+			// We didn't actually acquire a slot, most likely because ctx
+			// was done (the walk is stopping) while we were waiting for
+			// one. Either way there's nothing to release, and we mustn't
+			// call Renew without holding the semaphore.
+			return
+		}
+		next, diags := inst.Impl.Renew(ctx, providers.EphemeralRenew{
+			Private: private,
+		})
+		release()
+
+		inst.mu.Lock()
+		if diags.HasErrors() {
+			inst.renewErr = diags
+			inst.renewAt = time.Time{}
+			inst.mu.Unlock()
+			log.Printf("[ERROR] ephemeral.Resources: renewing %s failed: %s", addr, diags.Err())
+			return
+		}
+		if next == nil {
+			inst.renewAt = time.Time{}
+			inst.mu.Unlock()
+			r.audit.Renewed(addr, r.clock.Now())
+			return
+		}
+		inst.renewAt = next.RenewAt
+		inst.private = next.Private
+		inst.mu.Unlock()
+
+		r.audit.Renewed(addr, r.clock.Now())
+	}
+}
+
+// renewJitteredWait returns how long to sleep before renewing an instance
+// whose lease is due for renewal at renewAt, waking up slightly early by a
+// random amount to avoid a thundering herd of simultaneous renewals.
+func renewJitteredWait(now, renewAt time.Time) time.Duration {
+	wait := renewAt.Sub(now)
+	if wait <= 0 {
+		return 0
+	}
+
+	maxJitter := int64(float64(wait) * renewJitterFraction)
+	if maxJitter <= 0 {
+		return wait
+	}
+	wait -= time.Duration(rand.Int63n(maxJitter))
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
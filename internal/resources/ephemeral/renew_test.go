@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ephemeral
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/providers"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// fakeClock is a clock whose Now only advances when the test tells it to,
+// and whose After only fires once the simulated time reaches the requested
+// deadline, so renewal-interval tests don't need to wait in real time.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeClockWaiter
+}
+
+type fakeClockWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock(start time.Time) *fakeClock {
+	return &fakeClock{now: start}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	at := c.now.Add(d)
+	if !at.After(c.now) {
+		ch <- at
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeClockWaiter{at, ch})
+	return ch
+}
+
+// Advance moves the simulated clock forward by d, firing any pending
+// After channels whose deadline has now passed.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	var remaining []fakeClockWaiter
+	for _, w := range c.waiters {
+		if !w.at.After(c.now) {
+			w.ch <- w.at
+		} else {
+			remaining = append(remaining, w)
+		}
+	}
+	c.waiters = remaining
+}
+
+// countingRenewImpl is a fake ephemeral.ResourceInstance that records how
+// many times Renew was called and schedules its next renewal one interval
+// later according to the shared fake clock.
+type countingRenewImpl struct {
+	clock    *fakeClock
+	interval time.Duration
+	renews   int32
+	renewed  chan struct{}
+}
+
+func (impl *countingRenewImpl) Renew(ctx context.Context, req providers.EphemeralRenew) (*providers.EphemeralRenew, tfdiags.Diagnostics) {
+	atomic.AddInt32(&impl.renews, 1)
+	impl.renewed <- struct{}{}
+	return &providers.EphemeralRenew{RenewAt: impl.clock.Now().Add(impl.interval)}, nil
+}
+
+func (impl *countingRenewImpl) Close(ctx context.Context) tfdiags.Diagnostics {
+	return nil
+}
+
+func testResourceInstanceAddr(typeName, name string) addrs.AbsResourceInstance {
+	return addrs.AbsResourceInstance{
+		Module: addrs.RootModuleInstance,
+		Resource: addrs.ResourceInstance{
+			Resource: addrs.Resource{
+				Mode: addrs.EphemeralResourceMode,
+				Type: typeName,
+				Name: name,
+			},
+		},
+	}
+}
+
+func TestResourcesRegisterInstance_renewsOverSimulatedTime(t *testing.T) {
+	fc := newFakeClock(time.Unix(0, 0))
+	res := NewResources(NullAuditSink, nil)
+	res.clock = fc
+
+	impl := &countingRenewImpl{
+		clock:    fc,
+		interval: time.Minute,
+		renewed:  make(chan struct{}, 1),
+	}
+	addr := testResourceInstanceAddr("test_thing", "example")
+
+	res.RegisterInstance(context.Background(), addr, ResourceInstanceRegistration{
+		Impl:    impl,
+		RenewAt: fc.Now().Add(time.Minute),
+	})
+
+	const wantRenewals = 3
+	for i := 0; i < wantRenewals; i++ {
+		fc.Advance(time.Minute)
+		select {
+		case <-impl.renewed:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("renewal %d never happened", i+1)
+		}
+	}
+
+	if got := atomic.LoadInt32(&impl.renews); got != wantRenewals {
+		t.Fatalf("wrong number of renewals: got %d, want %d", got, wantRenewals)
+	}
+
+	diags := res.CloseInstances(context.Background(), addr.ConfigResource())
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics closing instance: %s", diags.Err())
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package ephemeral tracks the ephemeral resource instances that are open
+// for the duration of a single graph walk.
+//
+// Unlike managed and data resources, ephemeral resource instances are never
+// persisted anywhere: the only record of one existing is the in-memory
+// registration held by a Resources value for as long as the graph walk that
+// opened it is still running. Resources is also responsible for keeping
+// those instances renewed in the background and for closing them once the
+// graph walk no longer needs them.
+package ephemeral
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/providers"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// ResourceInstance is the interface through which Resources interacts with
+// an already-open ephemeral resource instance, so that the renewal loop and
+// close handling don't need to know anything about how the instance was
+// actually opened.
+type ResourceInstance interface {
+	// Renew asks the instance to renew whatever lease or lifetime it has
+	// remaining. A nil nextRenew return means the instance does not need
+	// to be renewed again.
+	Renew(ctx context.Context, req providers.EphemeralRenew) (nextRenew *providers.EphemeralRenew, diags tfdiags.Diagnostics)
+
+	// Close releases the instance. It's called at most once per instance.
+	Close(ctx context.Context) tfdiags.Diagnostics
+}
+
+// ResourceInstanceRegistration is the set of data a caller provides when an
+// ephemeral resource instance has just been opened and is ready to be
+// tracked by Resources.
+type ResourceInstanceRegistration struct {
+	// Value is the value to return to anything that refers to this
+	// instance for as long as it remains open.
+	Value cty.Value
+
+	// ConfigBody is the resource's configuration body, retained so that
+	// later diagnostics (e.g. a failed renewal) can be attributed back to
+	// the relevant configuration block.
+	ConfigBody hcl.Body
+
+	// Impl is the adapter used to renew and close this instance.
+	Impl ResourceInstance
+
+	// RenewAt is the time at which Impl.Renew should next be called. A
+	// zero value means the instance doesn't need to be renewed.
+	RenewAt time.Time
+
+	// Private is opaque provider-internal state to pass back in on the
+	// next renewal.
+	Private []byte
+
+	// TypeName and ProviderAddr identify the resource for the audit sink.
+	// They're redundant with information the caller could in principle
+	// derive from addr and ConfigBody, but passing them explicitly keeps
+	// Resources from needing to understand configs.Resource.
+	TypeName     string
+	ProviderAddr addrs.AbsProviderConfig
+}
+
+type registeredInstance struct {
+	ResourceInstanceRegistration
+
+	mu       sync.Mutex
+	renewAt  time.Time
+	private  []byte
+	renewErr tfdiags.Diagnostics
+
+	stop func()
+	done chan struct{}
+}
+
+// Resources tracks the ephemeral resource instances opened during a single
+// graph walk.
+type Resources struct {
+	mu        sync.Mutex
+	instances map[addrs.AbsResourceInstance]*registeredInstance
+	audit     AuditSink
+	sems      *Semaphores
+	clock     clock
+}
+
+// NewResources returns a new, empty Resources ready to track instances for
+// a new graph walk. Lifecycle events are reported to the given audit sink
+// (pass NullAuditSink if auditing hasn't been enabled for this run), and
+// concurrent opens/renews are bounded by sems (pass NewSemaphores(0) for no
+// limit).
+//
+// The only callers of this constructor in this tree are its own tests:
+// whatever builds the EvalContext for a real graph walk (and so would
+// construct the Resources that EvalContext.EphemeralResources returns) is
+// outside this diff, since the EvalContext implementation itself isn't
+// present in this snapshot. That call site needs to be updated to this
+// signature wherever it actually lives.
+func NewResources(audit AuditSink, sems *Semaphores) *Resources {
+	if audit == nil {
+		audit = NullAuditSink
+	}
+	return &Resources{
+		instances: make(map[addrs.AbsResourceInstance]*registeredInstance),
+		audit:     audit,
+		sems:      sems,
+		clock:     realClock{},
+	}
+}
+
+// Semaphores returns the concurrency limiter shared by every open and renew
+// call this Resources handles, so that callers like ephemeralResourceOpen
+// can acquire a slot before calling into the provider.
+func (r *Resources) Semaphores() *Semaphores {
+	return r.sems
+}
+
+// RegisterInstance records that the given ephemeral resource instance has
+// been opened, and -- if the registration includes a RenewAt time -- starts
+// a background renewal loop for it.
+//
+// The renewal loop runs until either CloseInstances is called for the
+// instance's containing resource or ctx is done, whichever comes first.
+func (r *Resources) RegisterInstance(ctx context.Context, addr addrs.AbsResourceInstance, reg ResourceInstanceRegistration) {
+	inst := &registeredInstance{
+		ResourceInstanceRegistration: reg,
+		renewAt:                      reg.RenewAt,
+		private:                      reg.Private,
+		done:                         make(chan struct{}),
+	}
+
+	r.mu.Lock()
+	r.instances[addr] = inst
+	r.mu.Unlock()
+
+	r.audit.Opened(addr, reg.TypeName, reg.ProviderAddr, r.clock.Now())
+
+	if reg.Impl == nil || reg.RenewAt.IsZero() {
+		close(inst.done)
+		return
+	}
+
+	renewCtx, stop := context.WithCancel(ctx)
+	inst.stop = stop
+	go r.renewLoop(renewCtx, addr, inst)
+}
+
+// InstanceDiagnostics returns any diagnostics produced by a background
+// renewal of the given instance, so that code evaluating a later reference
+// to it can surface the failure instead of silently using a stale value.
+//
+// Nothing calls this yet: the expression evaluator that resolves a
+// reference to an ephemeral resource instance isn't part of this diff, so a
+// failed background renewal is currently only recorded here and never
+// surfaced to the user. Whatever evaluates those references needs to call
+// this (likely right before or after it resolves the instance's Value) and
+// append the result to its own diagnostics.
+func (r *Resources) InstanceDiagnostics(addr addrs.AbsResourceInstance) tfdiags.Diagnostics {
+	r.mu.Lock()
+	inst, ok := r.instances[addr]
+	r.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	return inst.renewErr
+}
+
+// CloseInstances closes every currently-open instance belonging to the
+// given config resource, stopping any background renewal loops first.
+func (r *Resources) CloseInstances(ctx context.Context, addr addrs.ConfigResource) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	type closing struct {
+		addr addrs.AbsResourceInstance
+		inst *registeredInstance
+	}
+	var toClose []closing
+	r.mu.Lock()
+	for instAddr, inst := range r.instances {
+		if instAddr.ConfigResource().Equal(addr) {
+			toClose = append(toClose, closing{instAddr, inst})
+			delete(r.instances, instAddr)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, c := range toClose {
+		if c.inst.stop != nil {
+			c.inst.stop()
+			<-c.inst.done
+		}
+		closeDiags := c.inst.Impl.Close(ctx)
+		diags = diags.Append(closeDiags)
+		r.audit.Closed(c.addr, r.clock.Now(), closeDiags.Err())
+	}
+
+	return diags
+}
@@ -0,0 +1,207 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ephemeral
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// EphemeralMaxConcurrentEnvVar is the environment variable operators will be
+// able to set to a positive integer to impose a global concurrency limit on
+// ephemeral resource opens and renewals without needing a CLI flag.
+//
+// Neither a -ephemeral-max-concurrent command line flag nor a per-provider
+// ephemeral_max_concurrent meta-argument are wired up to anything in this
+// tree: the former belongs in the command package and the latter requires
+// the configs package parsing it and something calling SetProviderLimit
+// with the result, and neither package exists in this snapshot. Until that
+// wiring exists, GlobalLimitFromEnv has no caller either, so every provider
+// config is unconditionally unlimited in practice -- this file only
+// provides the semaphore mechanics and the env var lookup, not a complete,
+// user-reachable feature.
+const EphemeralMaxConcurrentEnvVar = "TF_EPHEMERAL_MAX_CONCURRENT"
+
+// GlobalLimitFromEnv returns the global concurrency limit configured via
+// EphemeralMaxConcurrentEnvVar, or 0 (meaning unlimited) if it's unset or
+// not a valid positive integer.
+func GlobalLimitFromEnv() int {
+	raw := os.Getenv(EphemeralMaxConcurrentEnvVar)
+	if raw == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		log.Printf("[WARN] ephemeral.Resources: ignoring invalid %s value %q", EphemeralMaxConcurrentEnvVar, raw)
+		return 0
+	}
+	return n
+}
+
+// stalledSlotWarningAfter is how long a call waiting for a semaphore slot
+// waits before we report it as a diagnostic, so operators investigating a
+// stalled plan/apply can tell it's blocked on a concurrency limit rather
+// than, say, a hung network call.
+const stalledSlotWarningAfter = 30 * time.Second
+
+// semaphore is a simple counting semaphore used to bound how many
+// OpenEphemeralResource (and Renew) calls can be in flight at once.
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	return make(semaphore, n)
+}
+
+// acquire blocks until a slot is available or ctx is done. If waiting takes
+// longer than stalledSlotWarningAfter it appends a warning diagnostic noting
+// that the walk is stalled behind this semaphore, identified by label.
+//
+// The acquired return value reports whether a slot was actually taken: the
+// caller must only call release if this is true, and must not proceed with
+// the work the semaphore is guarding if it's false.
+func (s semaphore) acquire(ctx context.Context, label string) (acquired bool, diags tfdiags.Diagnostics) {
+	if s == nil {
+		return true, nil
+	}
+
+	select {
+	case s <- struct{}{}:
+		return true, diags
+	default:
+	}
+
+	warned := false
+	timer := time.NewTimer(stalledSlotWarningAfter)
+	defer timer.Stop()
+	for {
+		select {
+		case s <- struct{}{}:
+			return true, diags
+		case <-ctx.Done():
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Ephemeral resource open canceled",
+				fmt.Sprintf("%s was canceled while still waiting for an available ephemeral_max_concurrent slot.", label),
+			))
+			return false, diags
+		case <-timer.C:
+			if !warned {
+				warned = true
+				log.Printf("[WARN] ephemeral.Resources: %s has been waiting more than %s for a concurrency slot", label, stalledSlotWarningAfter)
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Warning,
+					"Ephemeral resource open is stalled",
+					fmt.Sprintf("%s has been waiting more than %s for an available ephemeral_max_concurrent slot.", label, stalledSlotWarningAfter),
+				))
+			}
+		}
+	}
+}
+
+func (s semaphore) release() {
+	if s == nil {
+		return
+	}
+	<-s
+}
+
+// Semaphores manages the global and per-provider-config concurrency limits
+// for opening and renewing ephemeral resource instances.
+//
+// The zero value has no limits: every acquire succeeds immediately.
+type Semaphores struct {
+	mu             sync.Mutex
+	global         semaphore
+	perProvider    map[addrs.AbsProviderConfig]semaphore
+	providerLimits map[addrs.AbsProviderConfig]int
+}
+
+// NewSemaphores returns a Semaphores with the given global concurrency
+// limit. A limit of zero means unlimited.
+func NewSemaphores(globalLimit int) *Semaphores {
+	s := &Semaphores{
+		perProvider:    make(map[addrs.AbsProviderConfig]semaphore),
+		providerLimits: make(map[addrs.AbsProviderConfig]int),
+	}
+	if globalLimit > 0 {
+		s.global = newSemaphore(globalLimit)
+	}
+	return s
+}
+
+// SetProviderLimit configures the per-provider-config concurrency limit,
+// intended to be set from that provider block's ephemeral_max_concurrent
+// meta-argument once the configs package parses it (see the doc comment on
+// EphemeralMaxConcurrentEnvVar). It has no caller anywhere in this tree yet
+// -- it must be called before the first Acquire for that provider config;
+// a limit of zero means unlimited.
+func (s *Semaphores) SetProviderLimit(providerAddr addrs.AbsProviderConfig, limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.providerLimits[providerAddr] = limit
+}
+
+func (s *Semaphores) providerSemaphore(providerAddr addrs.AbsProviderConfig) semaphore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sem, ok := s.perProvider[providerAddr]; ok {
+		return sem
+	}
+	limit := s.providerLimits[providerAddr]
+	var sem semaphore
+	if limit > 0 {
+		sem = newSemaphore(limit)
+	}
+	if s.perProvider == nil {
+		s.perProvider = make(map[addrs.AbsProviderConfig]semaphore)
+	}
+	s.perProvider[providerAddr] = sem
+	return sem
+}
+
+// Acquire blocks until both the global slot and the given provider config's
+// slot are available, releasing whichever it already holds if ctx is
+// cancelled while waiting for the other.
+//
+// The returned release func is always safe to call exactly once, whether or
+// not a slot was actually acquired: if diags has errors, no slot is held and
+// release is a no-op, and the caller must not proceed with the call the
+// semaphore is meant to be guarding.
+func (s *Semaphores) Acquire(ctx context.Context, providerAddr addrs.AbsProviderConfig, label string) (release func(), diags tfdiags.Diagnostics) {
+	noop := func() {}
+	if s == nil {
+		return noop, nil
+	}
+
+	globalOK, globalDiags := s.global.acquire(ctx, label)
+	diags = diags.Append(globalDiags)
+	if !globalOK {
+		return noop, diags
+	}
+
+	providerSem := s.providerSemaphore(providerAddr)
+	providerOK, providerDiags := providerSem.acquire(ctx, label)
+	diags = diags.Append(providerDiags)
+	if !providerOK {
+		s.global.release()
+		return noop, diags
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			providerSem.release()
+			s.global.release()
+		})
+	}, diags
+}
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ephemeral
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+)
+
+func testProviderAddr(name string) addrs.AbsProviderConfig {
+	return addrs.AbsProviderConfig{
+		Module:   addrs.RootModule,
+		Provider: addrs.NewDefaultProvider(name),
+	}
+}
+
+func TestSemaphoresAcquire_ordering(t *testing.T) {
+	sems := NewSemaphores(1)
+	provider := testProviderAddr("test")
+
+	release1, diags := sems.Acquire(context.Background(), provider, "first")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %s", diags.Err())
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		release2, diags := sems.Acquire(context.Background(), provider, "second")
+		if diags.HasErrors() {
+			t.Errorf("unexpected diagnostics: %s", diags.Err())
+		}
+		close(acquired)
+		release2()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatalf("second Acquire returned before the first was released")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release1()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatalf("second Acquire never returned after the first was released")
+	}
+}
+
+func TestSemaphoresAcquire_canceledDoesNotStealSlot(t *testing.T) {
+	sems := NewSemaphores(1)
+	provider := testProviderAddr("test")
+
+	release1, diags := sems.Acquire(context.Background(), provider, "holder")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %s", diags.Err())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	release2, diags := sems.Acquire(ctx, provider, "canceled")
+	if !diags.HasErrors() {
+		t.Fatalf("expected an error from Acquire on an already-canceled context")
+	}
+
+	// A failed Acquire must not have taken the slot, so its release must be
+	// a harmless no-op rather than an unpaired channel receive that would
+	// either block forever or steal the real holder's slot.
+	release2()
+
+	done := make(chan struct{})
+	go func() {
+		release1()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("releasing the real holder blocked, the canceled Acquire must have stolen its slot")
+	}
+}
@@ -82,18 +82,58 @@ func ephemeralResourceOpen(ctx EvalContext, inp ephemeralResourceInput) tfdiags.
 		return diags
 	}
 
+	releaseSem, semDiags := ephemerals.Semaphores().Acquire(ctx.StopCtx(), inp.providerConfig, fmt.Sprintf("open of %s", inp.addr))
+	diags = diags.Append(semDiags)
+	if diags.HasErrors() {
+		return diags
+	}
+
 	resp := provider.OpenEphemeralResource(providers.OpenEphemeralResourceRequest{
 		TypeName: inp.addr.ContainingResource().Resource.Type,
 		Config:   unmarkedConfigVal,
 	})
-	if resp.Deferred != nil {
-		// FIXME: Actually implement this.
-		diags = diags.Append(fmt.Errorf("we don't support deferral of ephemeral resource instances yet"))
-	}
+	releaseSem()
+
 	diags = diags.Append(resp.Diagnostics.InConfigBody(config.Config, inp.addr.String()))
 	if diags.HasErrors() {
 		return diags
 	}
+
+	if resp.Deferred != nil {
+		log.Printf("[DEBUG] ephemeralResourceOpen: %s is deferred by the provider: %s", inp.addr, resp.Deferred.Reason)
+
+		// The provider can't give us a value yet, so we record the deferral
+		// with the shared deferred-changes tracker (internal/deferring.
+		// Deferred, returned by EvalContext.Deferrals()) and hand downstream
+		// references an unknown, ephemeral-marked placeholder instead of
+		// failing outright. Anything that depends on this value is expected
+		// to consult Deferred.ResourceInstanceDeferred and defer its own
+		// work in turn, the same way it already does for a deferred managed
+		// resource -- that propagation happens wherever references are
+		// evaluated, which is outside this file and isn't exercised by this
+		// package's tests, since the EvalContext implementation and the
+		// reference evaluator aren't part of this snapshot.
+		ctx.Deferrals().ReportEphemeralResourceInstanceDeferred(inp.addr, providers.DeferredReasonEphemeralOpen)
+
+		placeholder := cty.UnknownVal(schema.ImpliedType()).MarkWithPaths(configMarks).Mark(marks.Ephemeral)
+
+		ephemerals.RegisterInstance(ctx.StopCtx(), inp.addr, ephemeral.ResourceInstanceRegistration{
+			Value:      placeholder,
+			ConfigBody: config.Config,
+			Impl: &ephemeralResourceInstImpl{
+				addr:     inp.addr,
+				provider: provider,
+				internal: resp.Private,
+				deferred: true,
+			},
+			Private:      resp.Private,
+			TypeName:     inp.addr.Resource.Resource.Type,
+			ProviderAddr: inp.providerConfig,
+		})
+
+		return diags
+	}
+
 	resultVal := resp.Result.MarkWithPaths(configMarks)
 
 	errs := objchange.AssertPlanValid(schema, cty.NullVal(schema.ImpliedType()), configVal, resultVal)
@@ -125,14 +165,39 @@ func ephemeralResourceOpen(ctx EvalContext, inp ephemeralResourceInput) tfdiags.
 		internal: resp.Private,
 	}
 
+	// The provider has now actually opened this instance, so from this
+	// point on we must register it -- and therefore eventually close it --
+	// no matter what else goes wrong below, or we'd leak whatever external
+	// lease/tunnel/credential it represents for its entire TTL.
 	ephemerals.RegisterInstance(ctx.StopCtx(), inp.addr, ephemeral.ResourceInstanceRegistration{
-		Value:      resultVal,
-		ConfigBody: config.Config,
-		Impl:       impl,
-		RenewAt:    resp.RenewAt,
-		Private:    resp.Private,
+		Value:        resultVal,
+		ConfigBody:   config.Config,
+		Impl:         impl,
+		RenewAt:      resp.RenewAt,
+		Private:      resp.Private,
+		TypeName:     inp.addr.Resource.Resource.Type,
+		ProviderAddr: inp.providerConfig,
 	})
 
+	// Postcondition failures are evaluated after RegisterInstance above, not
+	// before, precisely so that a failing postcondition on an instance the
+	// provider has actually opened still results in it being tracked (and
+	// therefore closed) rather than leaking whatever it represents.
+	//
+	// TODO: cover the postcondition pass/fail paths here with a test once
+	// this package has a mock EvalContext to drive ephemeralResourceOpen
+	// end-to-end; there's currently no test harness in internal/terraform
+	// capable of exercising this function at all.
+	postconditionKeyData := keyData
+	postconditionKeyData.Self = resultVal
+
+	checkDiags = evalCheckRules(
+		addrs.ResourcePostcondition,
+		config.Postconditions,
+		ctx, inp.addr, postconditionKeyData,
+		tfdiags.Error,
+	)
+	diags = diags.Append(checkDiags)
 	return diags
 }
 
@@ -178,12 +243,21 @@ type ephemeralResourceInstImpl struct {
 	addr     addrs.AbsResourceInstance
 	provider providers.Interface
 	internal []byte
+
+	// deferred is true when the provider deferred opening this instance
+	// rather than actually opening it, in which case there is nothing for
+	// the provider to close or renew.
+	deferred bool
 }
 
 var _ ephemeral.ResourceInstance = (*ephemeralResourceInstImpl)(nil)
 
 // Close implements ephemeral.ResourceInstance.
 func (impl *ephemeralResourceInstImpl) Close(ctx context.Context) tfdiags.Diagnostics {
+	if impl.deferred {
+		log.Printf("[TRACE] ephemeralResourceInstImpl: %s was deferred, nothing to close", impl.addr)
+		return nil
+	}
 	log.Printf("[TRACE] ephemeralResourceInstImpl: closing %s", impl.addr)
 	resp := impl.provider.CloseEphemeralResource(providers.CloseEphemeralResourceRequest{
 		TypeName: impl.addr.Resource.Resource.Type,
@@ -194,6 +268,10 @@ func (impl *ephemeralResourceInstImpl) Close(ctx context.Context) tfdiags.Diagno
 
 // Renew implements ephemeral.ResourceInstance.
 func (impl *ephemeralResourceInstImpl) Renew(ctx context.Context, req providers.EphemeralRenew) (nextRenew *providers.EphemeralRenew, diags tfdiags.Diagnostics) {
+	if impl.deferred {
+		log.Printf("[TRACE] ephemeralResourceInstImpl: %s was deferred, nothing to renew", impl.addr)
+		return nil, nil
+	}
 	log.Printf("[TRACE] ephemeralResourceInstImpl: renewing %s", impl.addr)
 	resp := impl.provider.RenewEphemeralResource(providers.RenewEphemeralResourceRequest{
 		TypeName: impl.addr.Resource.Resource.Type,
@@ -201,8 +279,10 @@ func (impl *ephemeralResourceInstImpl) Renew(ctx context.Context, req providers.
 	})
 
 	if !resp.RenewAt.IsZero() {
-		nextRenew.RenewAt = resp.RenewAt
-		nextRenew.Private = resp.Private
+		nextRenew = &providers.EphemeralRenew{
+			RenewAt: resp.RenewAt,
+			Private: resp.Private,
+		}
 	}
 
 	return nextRenew, resp.Diagnostics
@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package terraform
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/providers"
+)
+
+func testEphemeralInstAddr() addrs.AbsResourceInstance {
+	return addrs.AbsResourceInstance{
+		Module: addrs.RootModuleInstance,
+		Resource: addrs.ResourceInstance{
+			Resource: addrs.Resource{
+				Mode: addrs.EphemeralResourceMode,
+				Type: "test_thing",
+				Name: "example",
+			},
+		},
+	}
+}
+
+// fakeEphemeralProvider implements just enough of providers.Interface to
+// exercise ephemeralResourceInstImpl's Close and Renew without needing a
+// full provider plugin (or the rest of the EvalContext machinery that
+// ephemeralResourceOpen itself depends on).
+type fakeEphemeralProvider struct {
+	providers.Interface
+
+	closeCalls int
+	renewCalls int
+	renewResp  providers.RenewEphemeralResourceResponse
+}
+
+func (p *fakeEphemeralProvider) CloseEphemeralResource(providers.CloseEphemeralResourceRequest) providers.CloseEphemeralResourceResponse {
+	p.closeCalls++
+	return providers.CloseEphemeralResourceResponse{}
+}
+
+func (p *fakeEphemeralProvider) RenewEphemeralResource(providers.RenewEphemeralResourceRequest) providers.RenewEphemeralResourceResponse {
+	p.renewCalls++
+	return p.renewResp
+}
+
+func TestEphemeralResourceInstImpl_deferredSkipsProvider(t *testing.T) {
+	provider := &fakeEphemeralProvider{}
+	impl := &ephemeralResourceInstImpl{
+		addr:     testEphemeralInstAddr(),
+		provider: provider,
+		deferred: true,
+	}
+
+	if diags := impl.Close(context.Background()); diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %s", diags.Err())
+	}
+	if provider.closeCalls != 0 {
+		t.Fatalf("Close on a deferred instance must not call the provider")
+	}
+
+	nextRenew, diags := impl.Renew(context.Background(), providers.EphemeralRenew{})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %s", diags.Err())
+	}
+	if nextRenew != nil {
+		t.Fatalf("Renew on a deferred instance must return a nil nextRenew, got %#v", nextRenew)
+	}
+	if provider.renewCalls != 0 {
+		t.Fatalf("Renew on a deferred instance must not call the provider")
+	}
+}
+
+func TestEphemeralResourceInstImpl_renewAllocatesNextRenew(t *testing.T) {
+	wantRenewAt := time.Unix(1700000000, 0)
+	provider := &fakeEphemeralProvider{
+		renewResp: providers.RenewEphemeralResourceResponse{
+			RenewAt: wantRenewAt,
+			Private: []byte("next-private"),
+		},
+	}
+	impl := &ephemeralResourceInstImpl{
+		addr:     testEphemeralInstAddr(),
+		provider: provider,
+	}
+
+	// This is a regression test: nextRenew is a named return value that
+	// must be allocated before its fields are set, or this call panics
+	// with a nil pointer dereference instead of returning a value.
+	nextRenew, diags := impl.Renew(context.Background(), providers.EphemeralRenew{Private: []byte("prev-private")})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %s", diags.Err())
+	}
+	if nextRenew == nil {
+		t.Fatalf("expected a non-nil nextRenew")
+	}
+	if !nextRenew.RenewAt.Equal(wantRenewAt) {
+		t.Fatalf("wrong RenewAt: got %s, want %s", nextRenew.RenewAt, wantRenewAt)
+	}
+	if string(nextRenew.Private) != "next-private" {
+		t.Fatalf("wrong Private: got %q", nextRenew.Private)
+	}
+	if provider.renewCalls != 1 {
+		t.Fatalf("expected exactly one call to the provider's RenewEphemeralResource")
+	}
+}